@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -43,6 +44,18 @@ type Config struct {
 	Timeout  time.Duration // The timeout duration to check the condition.
 	Interval time.Duration // The interval duration between two checkers.
 	Delay    time.Duration // The delay duration for the first start.
+
+	// BackoffBase, BackoffMax and BackoffFactor configure an optional
+	// exponential backoff that replaces Interval while the condition keeps
+	// failing consecutively: the next interval becomes
+	// min(BackoffMax, BackoffBase * BackoffFactor^consecutiveFailures).
+	// On the first successful check afterwards, the checker resumes Interval.
+	//
+	// If BackoffBase is equal to or less than 0, backoff is disabled and
+	// Interval is always used, which is the default behavior.
+	BackoffBase   time.Duration // The base backoff interval.
+	BackoffMax    time.Duration // The max backoff interval. If <= 0, use Interval instead.
+	BackoffFactor float64       // The exponential backoff factor. If <= 0, default to 2.
 }
 
 // Condition is used to check whether a condition is ok.
@@ -66,19 +79,57 @@ func AlwaysFalse() Condition {
 	return ConditionFunc(func(context.Context) bool { return false })
 }
 
+// DetailedCondition is implemented by the composite conditions, such as
+// those returned by AndCondition, OrCondition and QuorumCondition, which
+// can additionally report the result of each of their named sub-conditions.
+type DetailedCondition interface {
+	Condition
+	CheckDetails(context.Context) (ok bool, details map[string]bool)
+}
+
+// CallbackV2 is the same as the callback passed to NewChecker, but it is
+// also given the details of the sub-conditions, which is reported when
+// the checker condition implements DetailedCondition, such as a condition
+// built by AndCondition, OrCondition or QuorumCondition. details is nil
+// for a plain, non-composite condition.
+type CallbackV2 func(id string, ok bool, details map[string]bool)
+
+// ErrorCondition is implemented by a condition that can also report the
+// error that caused the last check to fail. Checker.Stats surfaces it as
+// Stats.LastError.
+type ErrorCondition interface {
+	Condition
+	CheckError(context.Context) (ok bool, err error)
+}
+
+// ErrorConditionFunc is a condition function that implements ErrorCondition.
+type ErrorConditionFunc func(context.Context) (ok bool, err error)
+
+// Check implements the interface Condition.
+func (f ErrorConditionFunc) Check(ctx context.Context) bool { ok, _ := f(ctx); return ok }
+
+// CheckError implements the interface ErrorCondition.
+func (f ErrorConditionFunc) CheckError(ctx context.Context) (bool, error) { return f(ctx) }
+
 // Checker is used to check whether a condition is ok.
 type Checker struct {
-	ckid string
-	ckcb func(string, bool)
-	conf atomic.Value // Config
-	cond atomic.Value // Condition
-
-	ctxlock sync.Mutex
-	cancelf context.CancelFunc
-	fail    uint64
-	ok      uint32
+	ckid  string
+	ckcb  func(string, bool)
+	conf  atomic.Value // Config
+	cond  atomic.Value // Condition
+	ckcb2 atomic.Value // CallbackV2
+
+	ctxlock  sync.Mutex
+	cancelf  context.CancelFunc
+	fail     uint64
+	ok       uint32
+	consfail uint64 // the number of the consecutive failures, used by backoff
 
 	jitter atomic.Value // func(interval time.Duration) time.Duration
+	update atomic.Value // func()
+
+	stats  atomic.Value // Stats
+	events atomic.Value // chan Event
 }
 
 // NewChecker returns a new condition checker with DefaultConfig.
@@ -94,6 +145,7 @@ func NewChecker(id string, condition Condition, callback func(id string, ok bool
 	c.SetCondition(condition)
 	c.SetConfig(DefaultConfig)
 	c.SetJitter(nil)
+	c.stats.Store(Stats{})
 	return c
 }
 
@@ -115,16 +167,92 @@ func (c *Checker) SetCondition(cond Condition) {
 	c.cond.Store(cond)
 }
 
+// SetCallbackV2 sets the v2 callback, which is called together with the
+// callback passed to NewChecker, if set, when the ok status has changed.
+func (c *Checker) SetCallbackV2(callback CallbackV2) { c.ckcb2.Store(callback) }
+
 // Ok reports whether the checker status is ok.
 func (c *Checker) Ok() bool { return atomic.LoadUint32(&c.ok) == 1 }
 
 // SetOk sets the status to ok.
-func (c *Checker) SetOk(ok bool) { c.updateStatus(ok, 0) }
+func (c *Checker) SetOk(ok bool) { c.updateStatus(ok, 0, nil) }
+
+// Stats is a snapshot of a Checker's check statistics, returned by
+// Checker.Stats.
+type Stats struct {
+	Checks              uint64        // The total number of the checks run.
+	Successes           uint64        // The total number of the successful checks.
+	Failures            uint64        // The total number of the failed checks.
+	ConsecutiveFailures uint64        // The number of the consecutive failed checks.
+	LastLatency         time.Duration // The latency of the last check.
+	LastChangeAt        time.Time     // The time when a check last changed the ok status (SetOk is not recorded).
+	LastError           error         // The error of the last check, or nil.
+}
+
+// Stats returns a snapshot of the checker's check statistics.
+func (c *Checker) Stats() Stats { return c.stats.Load().(Stats) }
+
+func (c *Checker) recordStats(ok bool, err error, latency time.Duration, changed bool) {
+	stats := c.Stats()
+	stats.Checks++
+	if ok {
+		stats.Successes++
+	} else {
+		stats.Failures++
+	}
+	stats.ConsecutiveFailures = atomic.LoadUint64(&c.consfail)
+	stats.LastLatency = latency
+	stats.LastError = err
+	if changed {
+		stats.LastChangeAt = time.Now()
+	}
+	c.stats.Store(stats)
+}
+
+// Events returns the event stream of the checker, creating it on the first
+// call. The channel is buffered, and an event is dropped, oldest first,
+// if the channel is full and no one is receiving from it in time, so a
+// slow or absent consumer never blocks the checks.
+func (c *Checker) Events() <-chan Event {
+	if ch, ok := c.events.Load().(chan Event); ok {
+		return ch
+	}
+
+	ch := make(chan Event, DefaultEventBufferSize)
+	if !c.events.CompareAndSwap(nil, ch) {
+		ch = c.events.Load().(chan Event)
+	}
+	return ch
+}
+
+func (c *Checker) publishEvent(typ EventType, ok bool, err error, latency time.Duration) {
+	ch, _ := c.events.Load().(chan Event)
+	if ch == nil {
+		return
+	}
+
+	event := Event{CheckerID: c.ckid, Type: typ, Ok: ok, Err: err, Latency: latency, Time: time.Now()}
+	select {
+	case ch <- event:
+	default:
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
 
 // Config returns the config of the checker.
 func (c *Checker) Config() Config { return c.conf.Load().(Config) }
 
 // SetConfig resets the config of the checker.
+//
+// If the checker has been added into a Manager, SetConfig triggers
+// the manager to re-schedule it based on the new config.
 func (c *Checker) SetConfig(config Config) {
 	if config.Interval <= 0 {
 		if DefaultInterval > 0 {
@@ -137,6 +265,18 @@ func (c *Checker) SetConfig(config Config) {
 		config.Failure = 0
 	}
 	c.conf.Store(config)
+
+	if f, _ := c.update.Load().(func()); f != nil {
+		f()
+	}
+}
+
+// setUpdateHook sets the function called on every SetConfig, which is used
+// by Manager to re-schedule the checker when its config changes.
+//
+// It is unexported because only a Manager in this package needs it.
+func (c *Checker) setUpdateHook(hook func()) {
+	c.update.Store(hook)
 }
 
 // SetJitter sets the jitter function to adjust the interval duration
@@ -167,6 +307,11 @@ func (c *Checker) Started() (yes bool) {
 
 // Start starts the checker until the context is done or the checker is stopped。
 //
+// Start lets the checker run standalone with its own goroutine and timer.
+// To drive a large number of checkers from a single scheduler goroutine
+// and a bounded worker pool instead, register the checker into a Manager
+// with Manager.Add and do not call Start on it.
+//
 // NOTICE: it will panic if the checker has been started.
 // The checker can be started more times, however, only if it is not started.
 func (c *Checker) Start(ctx context.Context) {
@@ -209,7 +354,7 @@ func (c *Checker) loop(ctx context.Context) {
 */
 
 func (c *Checker) loop(ctx context.Context) {
-	timer := time.NewTimer(c.getInterval(c.Config().Interval))
+	timer := time.NewTimer(c.nextInterval(c.Config()))
 	defer func() {
 		if timer.Stop() {
 			select {
@@ -226,8 +371,8 @@ func (c *Checker) loop(ctx context.Context) {
 
 		case <-timer.C:
 			config := c.Config()
-			timer = time.NewTimer(c.getInterval(config.Interval))
 			c.checkConfig(ctx, config)
+			timer = time.NewTimer(c.nextInterval(c.Config()))
 		}
 	}
 }
@@ -239,6 +384,36 @@ func (c *Checker) getInterval(interval time.Duration) time.Duration {
 	return interval
 }
 
+// nextInterval returns the interval to wait before the next check, which
+// backs off exponentially while the condition has been failing consecutively
+// and config.BackoffBase is set, and falls back to config.Interval otherwise.
+func (c *Checker) nextInterval(config Config) time.Duration {
+	if config.BackoffBase > 0 {
+		if fails := atomic.LoadUint64(&c.consfail); fails > 0 {
+			return c.getInterval(backoffInterval(config, fails))
+		}
+	}
+	return c.getInterval(config.Interval)
+}
+
+func backoffInterval(config Config, fails uint64) time.Duration {
+	max := config.BackoffMax
+	if max <= 0 {
+		max = config.Interval
+	}
+
+	factor := config.BackoffFactor
+	if factor <= 0 {
+		factor = 2
+	}
+
+	backoff := float64(config.BackoffBase) * math.Pow(factor, float64(fails))
+	if backoff <= 0 || backoff > float64(max) {
+		return max
+	}
+	return time.Duration(backoff)
+}
+
 func (c *Checker) beforeStart(ctx context.Context) (ok bool) {
 	config := c.Config()
 	if config.Delay > 0 {
@@ -256,7 +431,19 @@ func (c *Checker) beforeStart(ctx context.Context) (ok bool) {
 
 func (c *Checker) checkConfig(ctx context.Context, config Config) {
 	defer c.wrapPanic()
-	c.updateStatus(c.checkCondtion(ctx, config), config.Failure)
+
+	c.publishEvent(EventCheckStart, false, nil, 0)
+
+	start := time.Now()
+	ok, details, err := c.checkCondtion(ctx, config)
+	latency := time.Since(start)
+
+	changed := c.updateStatus(ok, config.Failure, details)
+	c.recordStats(ok, err, latency, changed)
+	c.publishEvent(EventCheckEnd, ok, err, latency)
+	if changed {
+		c.publishEvent(EventStatusChange, ok, err, latency)
+	}
 }
 
 func (c *Checker) wrapPanic() {
@@ -265,17 +452,32 @@ func (c *Checker) wrapPanic() {
 	}
 }
 
-func (c *Checker) checkCondtion(ctx context.Context, config Config) (ok bool) {
+func (c *Checker) checkCondtion(ctx context.Context, config Config) (ok bool, details map[string]bool, err error) {
 	if config.Timeout > 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
 		defer cancel()
 	}
-	return c.Condition().Check(ctx)
+
+	cond := c.Condition()
+	if dc, isDetailed := cond.(DetailedCondition); isDetailed {
+		ok, details = dc.CheckDetails(ctx)
+		return
+	}
+	if ec, isErr := cond.(ErrorCondition); isErr {
+		ok, err = ec.CheckError(ctx)
+		return
+	}
+	return cond.Check(ctx), nil, nil
 }
 
-func (c *Checker) updateStatus(success bool, failure uint64) {
-	var changed bool
+func (c *Checker) updateStatus(success bool, failure uint64, details map[string]bool) (changed bool) {
+	if success {
+		atomic.StoreUint64(&c.consfail, 0)
+	} else {
+		atomic.AddUint64(&c.consfail, 1)
+	}
+
 	if success {
 		c.ctxlock.Lock()
 		if c.fail > 0 {
@@ -304,8 +506,13 @@ func (c *Checker) updateStatus(success bool, failure uint64) {
 		}
 	}
 
-	if changed && c.ckcb != nil {
-		c.ckcb(c.ckid, success)
+	if changed {
+		if c.ckcb != nil {
+			c.ckcb(c.ckid, success)
+		}
+		if cb, _ := c.ckcb2.Load().(CallbackV2); cb != nil {
+			cb(c.ckid, success, details)
+		}
 	}
 	return
 }
@@ -57,3 +57,58 @@ func TestChecker(t *testing.T) {
 		t.Errorf("expect failure %d, but got %d", 3, failure)
 	}
 }
+
+func TestBackoffInterval(t *testing.T) {
+	config := Config{
+		Interval:      time.Second,
+		BackoffBase:   time.Millisecond * 100,
+		BackoffMax:    time.Second * 2,
+		BackoffFactor: 2,
+	}
+
+	if got := backoffInterval(config, 1); got != time.Millisecond*200 {
+		t.Errorf("expect %s, but got %s", time.Millisecond*200, got)
+	}
+	if got := backoffInterval(config, 2); got != time.Millisecond*400 {
+		t.Errorf("expect %s, but got %s", time.Millisecond*400, got)
+	}
+	if got := backoffInterval(config, 10); got != config.BackoffMax {
+		t.Errorf("expect the capped max %s, but got %s", config.BackoffMax, got)
+	}
+}
+
+func TestBackoffRecoversImmediately(t *testing.T) {
+	var checks int
+	var times []time.Time
+	cond := ConditionFunc(func(ctx context.Context) bool {
+		times = append(times, time.Now())
+		checks++
+		return checks > 3
+	})
+
+	checker := NewChecker("backoffrecoverid", cond, nil)
+	checker.SetConfig(Config{
+		Interval:      time.Millisecond * 20,
+		BackoffBase:   time.Millisecond * 10,
+		BackoffMax:    time.Second,
+		BackoffFactor: 3,
+	})
+
+	// The three failures back off by 30ms, 90ms and 270ms, so the stale
+	// count would make the wait after recovery 270ms instead of 20ms.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*600)
+	defer cancel()
+	checker.Start(ctx)
+
+	if len(times) < 5 {
+		t.Fatalf("expect at least 5 checks, but got %d", len(times))
+	}
+
+	// The 4th check (index 3) is the first success after 3 consecutive
+	// failures. The wait following it must already reflect the reset
+	// backoff counter and fall back to Interval, not the backed-off
+	// interval computed from the stale pre-recovery failure count.
+	if gap := times[4].Sub(times[3]); gap > time.Millisecond*80 {
+		t.Errorf("expect the wait after recovery to fall back to Interval, but got %s", gap)
+	}
+}
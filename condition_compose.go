@@ -0,0 +1,183 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// namedCondition wraps a Condition with a name, which is reported as
+// the key of the details map of a composite condition such as the one
+// returned by AndCondition, OrCondition or QuorumCondition.
+type namedCondition struct {
+	name string
+	cond Condition
+}
+
+// NamedCondition returns a condition named name, which delegates Check
+// to cond. Wrap the sub-conditions passed to AndCondition, OrCondition
+// and QuorumCondition with NamedCondition so that a composite condition
+// can report which of them flipped the overall result.
+func NamedCondition(name string, cond Condition) Condition {
+	return namedCondition{name: name, cond: cond}
+}
+
+func (n namedCondition) Check(ctx context.Context) bool { return n.cond.Check(ctx) }
+func (n namedCondition) Name() string                   { return n.name }
+
+// TimeoutCondition returns a condition that runs cond with its own timeout,
+// which is useful to bound a sub-condition of AndCondition, OrCondition or
+// QuorumCondition to less than the checker's own Config.Timeout.
+func TimeoutCondition(timeout time.Duration, cond Condition) Condition {
+	return ConditionFunc(func(ctx context.Context) bool {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return cond.Check(ctx)
+	})
+}
+
+// NotCondition returns a condition that is ok only if cond is not ok.
+func NotCondition(cond Condition) Condition {
+	return ConditionFunc(func(ctx context.Context) bool { return !cond.Check(ctx) })
+}
+
+// AndCondition returns a condition that is ok only if all of conds are ok.
+//
+// conds are evaluated concurrently. Once a false result is observed, the
+// other, still-running conds are canceled via their context, so that they
+// may return early if they honor ctx.Done.
+func AndCondition(conds ...Condition) Condition {
+	return andCondition(conds)
+}
+
+type andCondition []Condition
+
+func (a andCondition) Check(ctx context.Context) bool {
+	ok, _ := a.CheckDetails(ctx)
+	return ok
+}
+
+func (a andCondition) CheckDetails(ctx context.Context) (ok bool, details map[string]bool) {
+	decided, details := evalConcurrently(ctx, a, false)
+	return !decided, details
+}
+
+// OrCondition returns a condition that is ok if any of conds is ok.
+//
+// conds are evaluated concurrently. Once a true result is observed, the
+// other, still-running conds are canceled via their context, so that they
+// may return early if they honor ctx.Done.
+func OrCondition(conds ...Condition) Condition {
+	return orCondition(conds)
+}
+
+type orCondition []Condition
+
+func (o orCondition) Check(ctx context.Context) bool {
+	ok, _ := o.CheckDetails(ctx)
+	return ok
+}
+
+func (o orCondition) CheckDetails(ctx context.Context) (ok bool, details map[string]bool) {
+	return evalConcurrently(ctx, o, true)
+}
+
+// QuorumCondition returns a condition that is ok if at least quorum of
+// conds are ok.
+//
+// conds are evaluated concurrently. Once quorum conds have reported true,
+// the other, still-running conds are canceled via their context, so that
+// they may return early if they honor ctx.Done.
+func QuorumCondition(quorum int, conds ...Condition) Condition {
+	return quorumCondition{quorum: quorum, conds: conds}
+}
+
+type quorumCondition struct {
+	quorum int
+	conds  []Condition
+}
+
+func (q quorumCondition) Check(ctx context.Context) bool {
+	ok, _ := q.CheckDetails(ctx)
+	return ok
+}
+
+func (q quorumCondition) CheckDetails(ctx context.Context) (ok bool, details map[string]bool) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := startConditions(ctx, q.conds)
+
+	details = make(map[string]bool, len(q.conds))
+	var success int
+	for range q.conds {
+		r := <-results
+		details[r.name] = r.ok
+		if r.ok {
+			if success++; success >= q.quorum {
+				cancel()
+			}
+		}
+	}
+
+	return success >= q.quorum, details
+}
+
+// evalConcurrently runs conds concurrently and cancels the outstanding ones
+// once a result equal to stopOn is observed. It reports whether such a
+// result was observed (decided) and the result of every cond, keyed by its
+// name.
+func evalConcurrently(ctx context.Context, conds []Condition, stopOn bool) (decided bool, details map[string]bool) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := startConditions(ctx, conds)
+
+	details = make(map[string]bool, len(conds))
+	for range conds {
+		r := <-results
+		details[r.name] = r.ok
+		if r.ok == stopOn {
+			decided = true
+			cancel()
+		}
+	}
+
+	return decided, details
+}
+
+type condResult struct {
+	name string
+	ok   bool
+}
+
+func startConditions(ctx context.Context, conds []Condition) <-chan condResult {
+	results := make(chan condResult, len(conds))
+	for i, cond := range conds {
+		go func(i int, cond Condition) {
+			results <- condResult{name: conditionName(i, cond), ok: cond.Check(ctx)}
+		}(i, cond)
+	}
+	return results
+}
+
+func conditionName(i int, cond Condition) string {
+	if n, ok := cond.(interface{ Name() string }); ok {
+		return n.Name()
+	}
+	return fmt.Sprintf("cond%d", i)
+}
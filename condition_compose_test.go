@@ -0,0 +1,73 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAndCondition(t *testing.T) {
+	cond := AndCondition(NamedCondition("a", AlwaysTrue()), NamedCondition("b", AlwaysFalse()))
+	ok, details := cond.(DetailedCondition).CheckDetails(context.Background())
+	if ok {
+		t.Error("expect false, but got true")
+	}
+	if details["a"] != true || details["b"] != false {
+		t.Errorf("unexpect details: %v", details)
+	}
+}
+
+func TestOrCondition(t *testing.T) {
+	cond := OrCondition(NamedCondition("a", AlwaysFalse()), NamedCondition("b", AlwaysTrue()))
+	if ok := cond.Check(context.Background()); !ok {
+		t.Error("expect true, but got false")
+	}
+}
+
+func TestNotCondition(t *testing.T) {
+	if ok := NotCondition(AlwaysTrue()).Check(context.Background()); ok {
+		t.Error("expect false, but got true")
+	}
+}
+
+func TestQuorumCondition(t *testing.T) {
+	cond := QuorumCondition(2, AlwaysTrue(), AlwaysTrue(), AlwaysFalse())
+	if ok := cond.Check(context.Background()); !ok {
+		t.Error("expect true, but got false")
+	}
+
+	cond = QuorumCondition(2, AlwaysTrue(), AlwaysFalse(), AlwaysFalse())
+	if ok := cond.Check(context.Background()); ok {
+		t.Error("expect false, but got true")
+	}
+}
+
+func TestCheckerCallbackV2(t *testing.T) {
+	cond := AndCondition(NamedCondition("a", AlwaysTrue()), NamedCondition("b", AlwaysFalse()))
+
+	var gotDetails map[string]bool
+	checker := NewChecker("callbackv2id", cond, nil)
+	checker.SetCallbackV2(func(id string, ok bool, details map[string]bool) {
+		gotDetails = details
+	})
+	checker.SetConfig(Config{Failure: 0})
+	checker.SetOk(true)
+	checker.checkConfig(context.Background(), checker.Config())
+
+	if gotDetails["a"] != true || gotDetails["b"] != false {
+		t.Errorf("unexpect details: %v", gotDetails)
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+// TCPOption is used to configure NewTCPCondition.
+type TCPOption func(*tcpConfig)
+
+type tcpConfig struct {
+	send   []byte
+	expect []byte
+}
+
+// WithTCPSend makes NewTCPCondition send data right after the connection
+// has been established.
+func WithTCPSend(data []byte) TCPOption {
+	return func(c *tcpConfig) { c.send = data }
+}
+
+// WithTCPExpect makes NewTCPCondition, after sending the data configured by
+// WithTCPSend, read the same number of bytes as expect and compare them,
+// failing the condition on a mismatch, a read error or a timeout.
+func WithTCPExpect(expect []byte) TCPOption {
+	return func(c *tcpConfig) { c.expect = expect }
+}
+
+// NewTCPCondition returns a new condition that is ok if it can dial address
+// over tcp within the context deadline, optionally sending and expecting
+// the bytes configured by WithTCPSend and WithTCPExpect.
+//
+// The returned condition implements ErrorCondition, reporting the dial,
+// write or read error that caused the last check to fail.
+func NewTCPCondition(address string, opts ...TCPOption) Condition {
+	var config tcpConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return ErrorConditionFunc(func(ctx context.Context) (bool, error) {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return false, err
+		}
+		defer conn.Close()
+
+		return probeConn(ctx, conn, config.send, config.expect)
+	})
+}
+
+// NewUDPCondition returns a new condition that is ok if it can dial address
+// over udp, send the probe configured by WithTCPSend and, if WithTCPExpect
+// is given, read and match the reply within the context deadline.
+//
+// The returned condition implements ErrorCondition, reporting the dial,
+// write or read error that caused the last check to fail.
+func NewUDPCondition(address string, opts ...TCPOption) Condition {
+	var config tcpConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return ErrorConditionFunc(func(ctx context.Context) (bool, error) {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "udp", address)
+		if err != nil {
+			return false, err
+		}
+		defer conn.Close()
+
+		return probeConn(ctx, conn, config.send, config.expect)
+	})
+}
+
+func probeConn(ctx context.Context, conn net.Conn, send, expect []byte) (bool, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if len(send) > 0 {
+		if _, err := conn.Write(send); err != nil {
+			return false, err
+		}
+	}
+
+	if len(expect) == 0 {
+		return true, nil
+	}
+
+	reply := make([]byte, len(expect))
+	if _, err := readFull(conn, reply); err != nil {
+		return false, err
+	}
+	if !bytes.Equal(reply, expect) {
+		return false, fmt.Errorf("reply %q does not match the expected %q", reply, expect)
+	}
+	return true, nil
+}
+
+func readFull(conn net.Conn, buf []byte) (int, error) {
+	read := 0
+	for read < len(buf) {
+		n, err := conn.Read(buf[read:])
+		read += n
+		if err != nil {
+			return read, err
+		}
+	}
+	return read, nil
+}
+
+// NewDNSCondition returns a new condition that is ok if resolver can resolve
+// name for the given recordType ("ip", "ip4", "ip6", "cname" or "host"
+// host lookup) within the context deadline.
+//
+// If resolver is nil, use net.DefaultResolver instead.
+//
+// The returned condition implements ErrorCondition, reporting the lookup
+// error that caused the last check to fail.
+func NewDNSCondition(name, recordType string, resolver *net.Resolver) Condition {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	return ErrorConditionFunc(func(ctx context.Context) (bool, error) {
+		switch recordType {
+		case "cname":
+			_, err := resolver.LookupCNAME(ctx, name)
+			return err == nil, err
+		case "ip4", "ip6":
+			addrs, err := resolver.LookupIP(ctx, recordType, name)
+			if err == nil && len(addrs) == 0 {
+				err = fmt.Errorf("no %s addresses found for %q", recordType, name)
+			}
+			return err == nil, err
+		default:
+			addrs, err := resolver.LookupHost(ctx, name)
+			if err == nil && len(addrs) == 0 {
+				err = fmt.Errorf("no addresses found for %q", name)
+			}
+			return err == nil, err
+		}
+	})
+}
+
+// NewPingCondition returns a new condition that is ok if host replies to
+// count ICMP echo requests within the context deadline.
+//
+// It requires the permission to open a raw (or, on Linux, an unprivileged
+// "udp" protocol) ICMP socket. Where that permission is unavailable, such
+// as for an unprivileged process on Linux without CAP_NET_RAW or the
+// net.ipv4.ping_group_range sysctl, the condition logs nothing and skips
+// the check by always returning true, so it does not flag an unrelated
+// environment as unhealthy.
+//
+// The returned condition implements ErrorCondition, reporting the resolve,
+// write or read error that caused the last check to fail.
+func NewPingCondition(host string, count int) Condition {
+	if count <= 0 {
+		count = 1
+	}
+
+	return ErrorConditionFunc(func(ctx context.Context) (bool, error) {
+		conn, network, err := dialICMP()
+		if err != nil {
+			// No permission to probe ICMP in this environment: skip.
+			return true, nil
+		}
+		defer conn.Close()
+
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+
+		dst, id, err := resolvePingDst(conn, network, host)
+		if err != nil {
+			return false, err
+		}
+
+		for seq := 0; seq < count; seq++ {
+			if ok, err := pingOnce(conn, dst, id, seq); !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
+
+func dialICMP() (*icmp.PacketConn, string, error) {
+	if conn, err := icmp.ListenPacket("udp4", "0.0.0.0"); err == nil {
+		return conn, "udp4", nil
+	}
+	conn, err := icmp.ListenPacket("ip4:icmp", "0.0.0.0")
+	return conn, "ip4:icmp", err
+}
+
+// resolvePingDst resolves host into the address type and echo id expected
+// by network, the value dialICMP returned alongside conn.
+//
+// For the unprivileged "udp4" mode, icmp.PacketConn.WriteTo requires a
+// net.UDPAddr, and the kernel overwrites the echo id of outgoing packets
+// with the socket's local port, so replies must be matched against that
+// port rather than the pid-derived id used on the raw "ip4:icmp" socket.
+func resolvePingDst(conn *icmp.PacketConn, network, host string) (net.Addr, int, error) {
+	if network == "udp4" {
+		dst, err := net.ResolveUDPAddr("udp4", net.JoinHostPort(host, "0"))
+		if err != nil {
+			return nil, 0, err
+		}
+
+		local, ok := conn.LocalAddr().(*net.UDPAddr)
+		if !ok {
+			return nil, 0, fmt.Errorf("unexpected local address type %T for udp4 icmp socket", conn.LocalAddr())
+		}
+		return dst, local.Port, nil
+	}
+
+	dst, err := net.ResolveIPAddr("ip4", host)
+	if err != nil {
+		return nil, 0, err
+	}
+	return dst, os.Getpid() & 0xffff, nil
+}
+
+func pingOnce(conn *icmp.PacketConn, dst net.Addr, id, seq int) (bool, error) {
+	echoSeq := seq + 1
+
+	msg := icmp.Message{
+		Type: ipv4.ICMPTypeEcho,
+		Code: 0,
+		Body: &icmp.Echo{
+			ID: id, Seq: echoSeq,
+			Data: []byte("xgfone/go-checker"),
+		},
+	}
+
+	data, err := msg.Marshal(nil)
+	if err != nil {
+		return false, err
+	}
+	if _, err = conn.WriteTo(data, dst); err != nil {
+		return false, err
+	}
+
+	proto := 1 // ICMP for IPv4
+	reply := make([]byte, 1500)
+	for {
+		n, peer, err := conn.ReadFrom(reply)
+		if err != nil {
+			return false, err
+		}
+
+		// The "ip4:icmp" raw socket fallback receives all inbound ICMP on
+		// the host, not just replies to this probe, and the echo ID is
+		// shared by every concurrent ping in this process: a reply must
+		// come from dst and echo back this probe's ID/Seq before it is
+		// accepted as a match, or it may be mistaken for a concurrent
+		// ping's reply from a different host.
+		if !sameHost(peer, dst) {
+			continue
+		}
+
+		parsed, err := icmp.ParseMessage(proto, reply[:n])
+		if err != nil {
+			return false, err
+		}
+		if parsed.Type != ipv4.ICMPTypeEchoReply {
+			continue
+		}
+
+		echo, ok := parsed.Body.(*icmp.Echo)
+		if !ok || echo.ID != id || echo.Seq != echoSeq {
+			continue
+		}
+		return true, nil
+	}
+}
+
+func sameHost(addr, dst net.Addr) bool {
+	a, b := addrIP(addr), addrIP(dst)
+	return a != nil && b != nil && a.Equal(b)
+}
+
+func addrIP(addr net.Addr) net.IP {
+	switch a := addr.(type) {
+	case *net.IPAddr:
+		return a.IP
+	case *net.UDPAddr:
+		return a.IP
+	default:
+		return nil
+	}
+}
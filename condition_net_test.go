@@ -0,0 +1,109 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestNewTCPCondition(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		buf := make([]byte, 4)
+		if _, err := readFull(conn, buf); err == nil {
+			conn.Write(buf)
+		}
+	}()
+
+	cond := NewTCPCondition(ln.Addr().String(), WithTCPSend([]byte("ping")), WithTCPExpect([]byte("ping")))
+	if ok := cond.Check(context.Background()); !ok {
+		t.Error("expect the tcp condition is ok, but got false")
+	}
+}
+
+func TestNewTCPConditionRefused(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	cond := NewTCPCondition(addr)
+	ec, ok := cond.(ErrorCondition)
+	if !ok {
+		t.Fatal("expect the tcp condition to implement ErrorCondition")
+	}
+	if ok, err := ec.CheckError(context.Background()); ok || err == nil {
+		t.Errorf("expect the tcp condition to fail with a dial error, but got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestNewDNSCondition(t *testing.T) {
+	cond := NewDNSCondition("localhost", "host", nil)
+	if ok := cond.Check(context.Background()); !ok {
+		t.Error("expect the dns condition is ok, but got false")
+	}
+}
+
+func TestNewPingCondition(t *testing.T) {
+	conn, network, err := dialICMP()
+	if err != nil {
+		t.Skipf("no permission to open an ICMP socket in this environment: %v", err)
+	}
+	conn.Close()
+	t.Logf("using icmp network %q", network)
+
+	cond := NewPingCondition("127.0.0.1", 2)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	if ok := cond.Check(ctx); !ok {
+		t.Error("expect the ping condition against loopback to be ok, but got false")
+	}
+}
+
+func TestSameHost(t *testing.T) {
+	dst := &net.IPAddr{IP: net.ParseIP("127.0.0.1")}
+
+	tests := []struct {
+		addr net.Addr
+		want bool
+	}{
+		{&net.IPAddr{IP: net.ParseIP("127.0.0.1")}, true},
+		{&net.UDPAddr{IP: net.ParseIP("127.0.0.1")}, true},
+		{&net.IPAddr{IP: net.ParseIP("127.0.0.2")}, false},
+		{&net.UDPAddr{IP: net.ParseIP("10.0.0.1")}, false},
+	}
+	for _, tt := range tests {
+		if got := sameHost(tt.addr, dst); got != tt.want {
+			t.Errorf("sameHost(%v, %v): expect %v, but got %v", tt.addr, dst, tt.want, got)
+		}
+	}
+}
@@ -15,31 +15,180 @@
 package checker
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
 	"net/http"
+	"regexp"
 )
 
-// NewURLCondition returns a new url condition that checks
-// whether to access the url with the method GET returns the status code 2xx.
-func NewURLCondition(rawURL string) (Condition, error) {
-	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+// HTTPConditionConfig is used to configure NewHTTPCondition.
+type HTTPConditionConfig struct {
+	// URL is the url to be requested, which is mandatory.
+	URL string
+
+	// Method is the http method used to request the url.
+	//
+	// If empty, default to "GET".
+	Method string
+
+	// Headers is the extra headers sent with the request.
+	Headers http.Header
+
+	// Body is the body sent with the request.
+	Body []byte
+
+	// ExpectStatuses are the expected response status codes.
+	//
+	// If empty, the condition is ok when the response status code is 2xx.
+	ExpectStatuses []int
+
+	// ExpectBodyRegex, if set, is matched against the response body,
+	// and the condition is ok only if it matches.
+	ExpectBodyRegex *regexp.Regexp
+
+	// TLSConfig is used to configure the tls of the http client built
+	// for the request, such as the CA roots and the client certificate
+	// for mTLS. It is ignored if Client is set.
+	TLSConfig *tls.Config
+
+	// InsecureSkipVerify, if true, makes the http client built for the
+	// request skip the verification of the remote certificate.
+	// It is ignored if TLSConfig or Client is set.
+	InsecureSkipVerify bool
+
+	// FollowRedirects decides whether the http client built for the request
+	// follows the redirects. The default is false.
+	//
+	// It is ignored if Client is set.
+	FollowRedirects bool
+
+	// Client is the http client used to send the request.
+	//
+	// If set, TLSConfig, InsecureSkipVerify and FollowRedirects are ignored,
+	// and the caller is responsible for configuring the client itself.
+	Client *http.Client
+}
+
+// NewHTTPCondition returns a new condition that checks whether requesting
+// the url configured by config satisfies the configured status and body
+// matchers.
+//
+// The returned condition implements ErrorCondition, reporting the request,
+// status mismatch or body mismatch error that caused the last check to
+// fail.
+//
+// The returned condition clones the request with context.Context for each
+// check, so it is safe for concurrent use.
+func NewHTTPCondition(config HTTPConditionConfig) (Condition, error) {
+	method := config.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if len(config.Body) > 0 {
+		body = bytes.NewReader(config.Body)
+	}
+
+	req, err := http.NewRequest(method, config.URL, body)
 	if err != nil {
 		return nil, err
 	}
-	return ConditionFunc(func(ctx context.Context) (ok bool) {
-		resp, err := http.DefaultClient.Do(req.WithContext(ctx))
-		if resp != nil {
-			resp.Body.Close()
+	for key, values := range config.Headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	client := config.Client
+	if client == nil {
+		client = newHTTPClient(config)
+	}
+
+	return ErrorConditionFunc(func(ctx context.Context) (bool, error) {
+		r := req.Clone(ctx)
+		if len(config.Body) > 0 {
+			r.Body = io.NopCloser(bytes.NewReader(config.Body))
 		}
 
-		if err == nil {
-			ok = resp.StatusCode >= 200 && resp.StatusCode < 300
+		resp, err := client.Do(r)
+		if err != nil {
+			return false, err
 		}
+		defer resp.Body.Close()
 
-		return
+		if !isExpectStatus(resp.StatusCode, config.ExpectStatuses) {
+			return false, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		}
+
+		if config.ExpectBodyRegex == nil {
+			return true, nil
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return false, err
+		}
+		if !config.ExpectBodyRegex.Match(data) {
+			return false, fmt.Errorf("response body does not match %s", config.ExpectBodyRegex)
+		}
+		return true, nil
 	}), nil
 }
 
+// MustHTTPCondition is the same as NewHTTPCondition, but panics if there is an error.
+func MustHTTPCondition(config HTTPConditionConfig) Condition {
+	cond, err := NewHTTPCondition(config)
+	if err != nil {
+		panic(err)
+	}
+	return cond
+}
+
+func newHTTPClient(config HTTPConditionConfig) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.TLSConfig != nil {
+		transport.TLSClientConfig = config.TLSConfig
+	} else if config.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	client := &http.Client{Transport: transport}
+	if !config.FollowRedirects {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	return client
+}
+
+func isExpectStatus(status int, expects []int) bool {
+	if len(expects) == 0 {
+		return status >= 200 && status < 300
+	}
+	for _, expect := range expects {
+		if status == expect {
+			return true
+		}
+	}
+	return false
+}
+
+// NewURLCondition returns a new url condition that checks
+// whether to access the url with the method GET returns the status code 2xx.
+//
+// It is a thin wrapper over NewHTTPCondition kept for backward compatibility,
+// so it follows redirects like the http.DefaultClient it used to be based
+// on. Use NewHTTPCondition instead for TLS, custom headers, body or status
+// matching, or to opt out of following redirects.
+func NewURLCondition(rawURL string) (Condition, error) {
+	return NewHTTPCondition(HTTPConditionConfig{URL: rawURL, FollowRedirects: true})
+}
+
 // MustURLCondition is the same as NewURLCondition, but panics if there is an error.
 func MustURLCondition(rawURL string) Condition {
 	cond, err := NewURLCondition(rawURL)
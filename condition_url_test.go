@@ -0,0 +1,97 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestNewHTTPCondition(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Test") != "yes" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("hello world"))
+	}))
+	defer server.Close()
+
+	cond, err := NewHTTPCondition(HTTPConditionConfig{
+		URL:             server.URL,
+		Headers:         http.Header{"X-Test": []string{"yes"}},
+		ExpectStatuses:  []int{http.StatusTeapot},
+		ExpectBodyRegex: regexp.MustCompile("^hello"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ok := cond.Check(context.Background()); !ok {
+		t.Error("expect the condition is ok, but got false")
+	}
+}
+
+func TestNewHTTPConditionReportsStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	cond, err := NewHTTPCondition(HTTPConditionConfig{URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checker := NewChecker("httperrid", cond, nil)
+	checker.checkConfig(context.Background(), checker.Config())
+
+	if stats := checker.Stats(); stats.LastError == nil {
+		t.Error("expect Checker.Stats().LastError to report the status mismatch, but got nil")
+	}
+}
+
+func TestNewURLCondition(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cond := MustURLCondition(server.URL)
+	if ok := cond.Check(context.Background()); !ok {
+		t.Error("expect the condition is ok, but got false")
+	}
+}
+
+func TestNewURLConditionFollowsRedirects(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	redirector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer redirector.Close()
+
+	cond := MustURLCondition(redirector.URL)
+	if ok := cond.Check(context.Background()); !ok {
+		t.Error("expect the condition to follow the redirect and be ok, but got false")
+	}
+}
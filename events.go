@@ -0,0 +1,62 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import "time"
+
+// DefaultEventBufferSize is the buffer size of the channel returned by
+// Checker.Events.
+var DefaultEventBufferSize = 16
+
+// EventType is the type of an Event.
+type EventType uint8
+
+const (
+	// EventCheckStart is emitted right before a check runs.
+	EventCheckStart EventType = iota
+
+	// EventCheckEnd is emitted right after a check has run, whether or not
+	// the ok status changed.
+	EventCheckEnd
+
+	// EventStatusChange is emitted in addition to EventCheckEnd when a
+	// check changes the ok status of the checker.
+	EventStatusChange
+)
+
+// String returns the human-readable name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case EventCheckStart:
+		return "check-start"
+	case EventCheckEnd:
+		return "check-end"
+	case EventStatusChange:
+		return "status-change"
+	default:
+		return "unknown"
+	}
+}
+
+// Event represents a check-start, check-end or status-change event emitted
+// on the channel returned by Checker.Events.
+type Event struct {
+	CheckerID string        // The id of the checker that emitted the event.
+	Type      EventType     // The type of the event.
+	Ok        bool          // The result of the check. Unset for EventCheckStart.
+	Err       error         // The error of the check, or nil. Unset for EventCheckStart.
+	Latency   time.Duration // The latency of the check. Unset for EventCheckStart.
+	Time      time.Time     // The time when the event was emitted.
+}
@@ -0,0 +1,293 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultWorkers is the default number of the goroutines used by Manager
+// to run the checks concurrently.
+var DefaultWorkers = 64
+
+// Manager drives a large number of Checkers with a single scheduler
+// goroutine and a bounded worker pool, instead of letting every Checker
+// own a dedicated goroutine and timer via Checker.Start.
+//
+// A Manager is safe for concurrent use.
+type Manager struct {
+	jobs chan *schedEntry
+	wake chan struct{}
+
+	cancel context.CancelFunc
+	stop   sync.Once
+	done   chan struct{}
+
+	mu      sync.Mutex
+	entries map[string]*schedEntry
+	sched   schedHeap
+}
+
+type schedEntry struct {
+	checker  *Checker
+	deadline time.Time
+	index    int
+}
+
+type schedHeap []*schedEntry
+
+func (h schedHeap) Len() int            { return len(h) }
+func (h schedHeap) Less(i, j int) bool  { return h[i].deadline.Before(h[j].deadline) }
+func (h schedHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i]; h[i].index, h[j].index = i, j }
+func (h *schedHeap) Push(x interface{}) { e := x.(*schedEntry); e.index = len(*h); *h = append(*h, e) }
+func (h *schedHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*h = old[:n-1]
+	return e
+}
+
+// NewManager returns a new Manager and starts its scheduler and worker
+// goroutines immediately.
+//
+// workers is the size of the worker pool that runs the due checks.
+// If workers is equal to or less than 0, use DefaultWorkers instead.
+func NewManager(workers int) *Manager {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		jobs:    make(chan *schedEntry, workers),
+		wake:    make(chan struct{}, 1),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		entries: make(map[string]*schedEntry, 64),
+	}
+
+	for i := 0; i < workers; i++ {
+		go m.runWorker(ctx)
+	}
+	go m.runScheduler(ctx)
+
+	return m
+}
+
+// Add registers the checker and schedules it onto the manager.
+//
+// It does nothing if the checker with the same id has been added.
+func (m *Manager) Add(c *Checker) {
+	id := c.ID()
+
+	m.mu.Lock()
+	if _, ok := m.entries[id]; ok {
+		m.mu.Unlock()
+		return
+	}
+
+	e := &schedEntry{checker: c, deadline: m.firstDeadline(c)}
+	heap.Push(&m.sched, e)
+	m.entries[id] = e
+	m.mu.Unlock()
+
+	c.setUpdateHook(func() {
+		// Move the pending deadline to match the new config. An entry that
+		// is in flight (index -1) is re-scheduled by reschedule instead.
+		m.mu.Lock()
+		if m.entries[id] == e && e.index >= 0 {
+			e.deadline = time.Now().Add(c.nextInterval(c.Config()))
+			heap.Fix(&m.sched, e.index)
+		}
+		m.mu.Unlock()
+		m.wakeup()
+	})
+	m.wakeup()
+}
+
+// Remove unregisters the checker by the id and stops scheduling it.
+//
+// It does nothing if there is no checker with the id.
+func (m *Manager) Remove(id string) {
+	m.mu.Lock()
+	e, ok := m.entries[id]
+	if ok {
+		delete(m.entries, id)
+		if e.index >= 0 {
+			heap.Remove(&m.sched, e.index)
+		}
+	}
+	m.mu.Unlock()
+
+	if ok {
+		e.checker.setUpdateHook(nil)
+	}
+}
+
+// Range calls f for all the registered checkers until f returns false.
+func (m *Manager) Range(f func(*Checker) bool) {
+	m.mu.Lock()
+	checkers := make([]*Checker, 0, len(m.entries))
+	for _, e := range m.entries {
+		checkers = append(checkers, e.checker)
+	}
+	m.mu.Unlock()
+
+	for _, c := range checkers {
+		if !f(c) {
+			return
+		}
+	}
+}
+
+// Stop stops the manager, including its scheduler and all the workers.
+//
+// It does not stop the registered checkers themselves.
+func (m *Manager) Stop() {
+	m.stop.Do(func() {
+		m.cancel()
+		close(m.done)
+	})
+}
+
+func (m *Manager) firstDeadline(c *Checker) time.Time {
+	config := c.Config()
+	if config.Delay > 0 {
+		return time.Now().Add(config.Delay)
+	}
+	return time.Now()
+}
+
+func (m *Manager) wakeup() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Manager) runScheduler(ctx context.Context) {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		wait := m.nextWait()
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.wake:
+		case <-timer.C:
+			m.dispatchDue(ctx)
+		}
+	}
+}
+
+func (m *Manager) nextWait() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.sched) == 0 {
+		return time.Hour
+	}
+	if wait := time.Until(m.sched[0].deadline); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+func (m *Manager) dispatchDue(ctx context.Context) {
+	now := time.Now()
+
+	m.mu.Lock()
+	due := make([]*schedEntry, 0, len(m.sched))
+	for len(m.sched) > 0 && !m.sched[0].deadline.After(now) {
+		due = append(due, heap.Pop(&m.sched).(*schedEntry))
+	}
+	m.mu.Unlock()
+
+	for _, e := range due {
+		m.dispatch(ctx, e)
+	}
+}
+
+func (m *Manager) dispatch(ctx context.Context, e *schedEntry) {
+	select {
+	case m.jobs <- e:
+	default:
+		// The worker pool is saturated: do not block the scheduler,
+		// hand the job off to a short-lived goroutine instead, but still
+		// respect ctx so it cannot block forever past Manager.Stop, when
+		// no runWorker is left to receive from m.jobs.
+		go func() {
+			select {
+			case m.jobs <- e:
+			case <-ctx.Done():
+			}
+		}()
+	}
+}
+
+// reschedule computes the entry's next deadline from the outcome of the
+// check that just completed and, if e is still the entry registered for
+// its checker's id, re-inserts it into the heap. It must run after the
+// check has finished, since the next interval depends on the
+// consecutive-failure count that the check just updated.
+func (m *Manager) reschedule(e *schedEntry) {
+	e.deadline = time.Now().Add(e.checker.nextInterval(e.checker.Config()))
+
+	m.mu.Lock()
+	// e may have been popped off the heap for an in-flight check and then,
+	// before that check completes, removed and re-added (e.g. to force a
+	// reconfigure): entries[id] would then hold a different, newer entry
+	// for the same id, and pushing e back in would leave a phantom entry
+	// in the heap that keeps firing duplicate checks for this checker.
+	ok := m.entries[e.checker.ID()] == e
+	if ok {
+		heap.Push(&m.sched, e)
+	}
+	m.mu.Unlock()
+
+	// reschedule now runs asynchronously from the scheduler loop (after the
+	// check completes on a worker), so the scheduler may already be asleep
+	// on an empty or later-headed heap; nudge it to recompute its wait.
+	if ok {
+		m.wakeup()
+	}
+}
+
+func (m *Manager) runWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-m.jobs:
+			e.checker.checkConfig(ctx, e.checker.Config())
+			m.reschedule(e)
+		}
+	}
+}
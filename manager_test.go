@@ -0,0 +1,129 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestManager(t *testing.T) {
+	var count uint32
+	cond := ConditionFunc(func(ctx context.Context) bool {
+		atomic.AddUint32(&count, 1)
+		return true
+	})
+
+	checker := NewChecker("managerid", cond, nil)
+	checker.SetConfig(Config{Interval: time.Millisecond * 100})
+
+	manager := NewManager(2)
+	manager.Add(checker)
+
+	<-time.NewTimer(time.Millisecond * 550).C
+	manager.Remove("managerid")
+	manager.Stop()
+
+	if n := atomic.LoadUint32(&count); n < 3 || n > 7 {
+		t.Errorf("unexpect check count: %d", n)
+	}
+}
+
+func TestManagerRange(t *testing.T) {
+	manager := NewManager(1)
+	defer manager.Stop()
+
+	manager.Add(NewChecker("id1", nil, nil))
+	manager.Add(NewChecker("id2", nil, nil))
+
+	var ids []string
+	manager.Range(func(c *Checker) bool {
+		ids = append(ids, c.ID())
+		return true
+	})
+
+	if len(ids) != 2 {
+		t.Errorf("expect 2 checkers, but got %d", len(ids))
+	}
+
+	manager.Remove("id1")
+	manager.Remove("id2")
+
+	ids = nil
+	manager.Range(func(c *Checker) bool {
+		ids = append(ids, c.ID())
+		return true
+	})
+	if len(ids) != 0 {
+		t.Errorf("expect no checker, but got %d", len(ids))
+	}
+}
+
+func TestManagerDispatchOverflowDoesNotLeakPastStop(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	// An unbuffered jobs channel with no receiver forces every dispatch
+	// onto the overflow path.
+	m := &Manager{jobs: make(chan *schedEntry)}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for i := 0; i < 50; i++ {
+		m.dispatch(ctx, &schedEntry{checker: NewChecker(fmt.Sprintf("overflow%d", i), nil, nil)})
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if runtime.NumGoroutine() <= before+5 {
+			return
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	t.Errorf("expect the overflow goroutines to exit once ctx is done, but goroutine count is %d (was %d before)",
+		runtime.NumGoroutine(), before)
+}
+
+func TestManagerRescheduleIgnoresStaleEntry(t *testing.T) {
+	checker := NewChecker("staleid", nil, nil)
+
+	m := &Manager{entries: make(map[string]*schedEntry, 1)}
+
+	// Simulate the entry being in flight: popped off the heap (index -1)
+	// for a check that hasn't completed yet.
+	stale := &schedEntry{checker: checker, index: -1}
+	m.entries[checker.ID()] = stale
+
+	// While the check is in flight, the caller removes and re-adds the
+	// checker, e.g. to force a reconfigure, replacing the registered entry.
+	fresh := &schedEntry{checker: checker, index: -1}
+	m.entries[checker.ID()] = fresh
+	heap.Push(&m.sched, fresh)
+
+	// The in-flight check now completes and reschedules the stale entry it
+	// was handed; it must not be pushed back in alongside fresh.
+	m.reschedule(stale)
+
+	if n := len(m.sched); n != 1 {
+		t.Fatalf("expect 1 entry in the heap, but got %d", n)
+	}
+	if m.sched[0] != fresh {
+		t.Error("expect the heap to still hold the fresh entry, but it was replaced")
+	}
+}
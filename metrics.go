@@ -0,0 +1,110 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Collector implements prometheus.Collector, exposing Checker.Stats and
+// Checker.Ok of a group of checkers as Prometheus metrics.
+type Collector struct {
+	checkers func() []*Checker
+
+	checksTotal         *prometheus.Desc
+	successesTotal      *prometheus.Desc
+	failuresTotal       *prometheus.Desc
+	consecutiveFailures *prometheus.Desc
+	lastLatency         *prometheus.Desc
+	up                  *prometheus.Desc
+}
+
+// NewCollector returns a new Collector reporting the Stats of all the
+// checkers currently registered into manager.
+//
+// The set of checkers is re-read from manager on every Collect, so checkers
+// added to or removed from manager afterwards are picked up automatically.
+func NewCollector(manager *Manager) *Collector {
+	return newCollector(func() []*Checker {
+		checkers := make([]*Checker, 0, 64)
+		manager.Range(func(c *Checker) bool {
+			checkers = append(checkers, c)
+			return true
+		})
+		return checkers
+	})
+}
+
+// NewCheckersCollector returns a new Collector reporting the Stats of the
+// given checkers, for use when they are not registered into a Manager.
+func NewCheckersCollector(checkers ...*Checker) *Collector {
+	return newCollector(func() []*Checker { return checkers })
+}
+
+func newCollector(checkers func() []*Checker) *Collector {
+	const subsystem = ""
+	labels := []string{"id"}
+
+	return &Collector{
+		checkers: checkers,
+
+		checksTotal: prometheus.NewDesc(
+			prometheus.BuildFQName("checker", subsystem, "checks_total"),
+			"The total number of the checks run by the checker.", labels, nil),
+		successesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName("checker", subsystem, "successes_total"),
+			"The total number of the successful checks.", labels, nil),
+		failuresTotal: prometheus.NewDesc(
+			prometheus.BuildFQName("checker", subsystem, "failures_total"),
+			"The total number of the failed checks.", labels, nil),
+		consecutiveFailures: prometheus.NewDesc(
+			prometheus.BuildFQName("checker", subsystem, "consecutive_failures"),
+			"The number of the consecutive failed checks.", labels, nil),
+		lastLatency: prometheus.NewDesc(
+			prometheus.BuildFQName("checker", subsystem, "last_check_latency_seconds"),
+			"The latency of the last check in seconds.", labels, nil),
+		up: prometheus.NewDesc(
+			prometheus.BuildFQName("checker", subsystem, "up"),
+			"Whether the checker ok status is 1 (ok) or 0 (not ok).", labels, nil),
+	}
+}
+
+// Describe implements the interface prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.checksTotal
+	ch <- c.successesTotal
+	ch <- c.failuresTotal
+	ch <- c.consecutiveFailures
+	ch <- c.lastLatency
+	ch <- c.up
+}
+
+// Collect implements the interface prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, checker := range c.checkers() {
+		id := checker.ID()
+		stats := checker.Stats()
+
+		ch <- prometheus.MustNewConstMetric(c.checksTotal, prometheus.CounterValue, float64(stats.Checks), id)
+		ch <- prometheus.MustNewConstMetric(c.successesTotal, prometheus.CounterValue, float64(stats.Successes), id)
+		ch <- prometheus.MustNewConstMetric(c.failuresTotal, prometheus.CounterValue, float64(stats.Failures), id)
+		ch <- prometheus.MustNewConstMetric(c.consecutiveFailures, prometheus.GaugeValue, float64(stats.ConsecutiveFailures), id)
+		ch <- prometheus.MustNewConstMetric(c.lastLatency, prometheus.GaugeValue, stats.LastLatency.Seconds(), id)
+
+		var up float64
+		if checker.Ok() {
+			up = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, up, id)
+	}
+}
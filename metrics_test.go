@@ -0,0 +1,68 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCheckerStats(t *testing.T) {
+	errBad := errors.New("bad")
+	cond := ErrorConditionFunc(func(context.Context) (bool, error) { return false, errBad })
+
+	checker := NewChecker("statsid", cond, nil)
+	checker.SetConfig(Config{Failure: 0})
+	checker.checkConfig(context.Background(), checker.Config())
+
+	stats := checker.Stats()
+	if stats.Checks != 1 || stats.Failures != 1 || stats.Successes != 0 {
+		t.Errorf("unexpect stats: %+v", stats)
+	}
+	if stats.LastError != errBad {
+		t.Errorf("expect error %v, but got %v", errBad, stats.LastError)
+	}
+}
+
+func TestCheckerEvents(t *testing.T) {
+	checker := NewChecker("eventsid", AlwaysTrue(), nil)
+	checker.SetConfig(Config{Failure: 0})
+
+	events := checker.Events()
+	checker.checkConfig(context.Background(), checker.Config())
+
+	var types []EventType
+	for i := 0; i < 2; i++ {
+		types = append(types, (<-events).Type)
+	}
+
+	if len(types) != 2 || types[0] != EventCheckStart || types[1] != EventCheckEnd {
+		t.Errorf("unexpect events: %v", types)
+	}
+}
+
+func TestCollector(t *testing.T) {
+	checker := NewChecker("collectorid", AlwaysTrue(), nil)
+	checker.SetConfig(Config{Failure: 0})
+	checker.checkConfig(context.Background(), checker.Config())
+
+	collector := NewCheckersCollector(checker)
+	if n := testutil.CollectAndCount(collector); n == 0 {
+		t.Error("expect some metrics collected, but got none")
+	}
+}